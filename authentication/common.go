@@ -0,0 +1,37 @@
+package authentication
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// authorizationHeaderFormat is shared by every Signer implementation so
+// that Manta and CloudAPI both receive HTTP Signature authentication
+// headers in the same shape.
+const authorizationHeaderFormat = `Signature keyId="%s",algorithm="%s",signature="%s"`
+
+// keyIDForSigner builds the "keyId" component of the Authorization
+// header. RBAC subusers must be signed with the
+// /account/users/user/keys/keyid form rather than /account/keys/keyid,
+// so a non-empty userName switches between the two.
+func keyIDForSigner(accountName, userName, fingerprint string) string {
+	if userName != "" {
+		return fmt.Sprintf("/%s/users/%s/keys/%s", accountName, userName, fingerprint)
+	}
+	return fmt.Sprintf("/%s/keys/%s", accountName, fingerprint)
+}
+
+func signatureAlgorithmFor(keyType string) string {
+	switch keyType {
+	case "ssh-rsa":
+		return "rsa-sha1"
+	case "ssh-ed25519":
+		return "ed25519-sha512"
+	default:
+		return "ecdsa-sha256"
+	}
+}
+
+func base64Encode(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
@@ -0,0 +1,75 @@
+package authentication
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestKeyIDForSigner(t *testing.T) {
+	tests := []struct {
+		name        string
+		accountName string
+		userName    string
+		fingerprint string
+		want        string
+	}{
+		{
+			name:        "account only",
+			accountName: "acmecorp",
+			userName:    "",
+			fingerprint: "aa:bb:cc",
+			want:        "/acmecorp/keys/aa:bb:cc",
+		},
+		{
+			name:        "RBAC subuser",
+			accountName: "acmecorp",
+			userName:    "alice",
+			fingerprint: "aa:bb:cc",
+			want:        "/acmecorp/users/alice/keys/aa:bb:cc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := keyIDForSigner(tt.accountName, tt.userName, tt.fingerprint)
+			if got != tt.want {
+				t.Fatalf("keyIDForSigner(%q, %q, %q) = %q, want %q", tt.accountName, tt.userName, tt.fingerprint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignatureAlgorithmFor(t *testing.T) {
+	tests := []struct {
+		keyType string
+		want    string
+	}{
+		{"ssh-rsa", "rsa-sha1"},
+		{"ssh-ed25519", "ed25519-sha512"},
+		{"ecdsa-sha2-nistp256", "ecdsa-sha256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keyType, func(t *testing.T) {
+			if got := signatureAlgorithmFor(tt.keyType); got != tt.want {
+				t.Fatalf("signatureAlgorithmFor(%q) = %q, want %q", tt.keyType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBase64Encode(t *testing.T) {
+	got := base64Encode([]byte("hello"))
+	want := "aGVsbG8="
+	if got != want {
+		t.Fatalf("base64Encode(%q) = %q, want %q", "hello", got, want)
+	}
+}
+
+func TestAuthorizationHeaderFormat(t *testing.T) {
+	got := fmt.Sprintf(authorizationHeaderFormat, "/acmecorp/keys/aa:bb:cc", "rsa-sha1", "c2lnbmF0dXJl")
+	want := `Signature keyId="/acmecorp/keys/aa:bb:cc",algorithm="rsa-sha1",signature="c2lnbmF0dXJl"`
+	if got != want {
+		t.Fatalf("authorizationHeaderFormat produced %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package authentication
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/errwrap"
+	"golang.org/x/crypto/ssh"
+)
+
+// PrivateKeySignerInput represents the information required by the
+// NewPrivateKeySigner constructor in order to construct an
+// authentication.Signer which signs requests with an in-memory private
+// key.
+type PrivateKeySignerInput struct {
+	// KeyID is the fingerprint of PrivateKey, in the form the Triton API
+	// expects (e.g. an MD5 or SHA256 fingerprint).
+	KeyID string
+
+	// PrivateKey is the PEM-encoded private key material.
+	PrivateKey []byte
+
+	// AccountName is the Triton account (login) the signature is made on
+	// behalf of.
+	AccountName string
+
+	// Username, if set, is the RBAC subuser under AccountName that owns
+	// KeyID. When empty the signature is made as the account itself.
+	Username string
+}
+
+// PrivateKeySigner implements the Signer interface and is used to sign
+// requests with an in-memory private key.
+type PrivateKeySigner struct {
+	formattedKeyFingerprint string
+	keyIdentifier           string
+	key                     ssh.Signer
+	accountName             string
+	userName                string
+}
+
+// NewPrivateKeySigner returns a Signer which signs requests directly
+// with the given PEM-encoded private key.
+func NewPrivateKeySigner(input PrivateKeySignerInput) (*PrivateKeySigner, error) {
+	signer, err := ssh.ParsePrivateKey(input.PrivateKey)
+	if err != nil {
+		return nil, errwrap.Wrapf("error parsing private key: {{err}}", err)
+	}
+
+	return &PrivateKeySigner{
+		formattedKeyFingerprint: ssh.FingerprintLegacyMD5(signer.PublicKey()),
+		keyIdentifier:           input.KeyID,
+		key:                     signer,
+		accountName:             input.AccountName,
+		userName:                input.Username,
+	}, nil
+}
+
+// KeyFingerprint returns the fingerprint of the key this signer was
+// constructed with.
+func (s *PrivateKeySigner) KeyFingerprint() string {
+	return s.formattedKeyFingerprint
+}
+
+// Sign produces an "Authorization" header value for the given "date"
+// header contents, using the key identifier form appropriate for the
+// account (or RBAC subuser) this signer was constructed for.
+func (s *PrivateKeySigner) Sign(dateHeader string) (string, error) {
+	signature, err := s.key.Sign(nil, []byte(fmt.Sprintf("date: %s", dateHeader)))
+	if err != nil {
+		return "", errwrap.Wrapf("error signing date header: {{err}}", err)
+	}
+
+	keyIDPath := keyIDForSigner(s.accountName, s.userName, s.keyIdentifier)
+
+	return fmt.Sprintf(authorizationHeaderFormat,
+		keyIDPath,
+		signatureAlgorithmFor(s.key.PublicKey().Type()),
+		base64Encode(signature.Blob),
+	), nil
+}
@@ -0,0 +1,86 @@
+package authentication
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func generateTestPrivateKeyPEM(t *testing.T) []byte {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+}
+
+func TestNewPrivateKeySigner(t *testing.T) {
+	pemBytes := generateTestPrivateKeyPEM(t)
+
+	signer, err := NewPrivateKeySigner(PrivateKeySignerInput{
+		KeyID:       "aa:bb:cc",
+		PrivateKey:  pemBytes,
+		AccountName: "acmecorp",
+		Username:    "",
+	})
+	if err != nil {
+		t.Fatalf("NewPrivateKeySigner() error = %v", err)
+	}
+
+	parsed, err := ssh.ParsePrivateKey(pemBytes)
+	if err != nil {
+		t.Fatalf("ssh.ParsePrivateKey() error = %v", err)
+	}
+	want := ssh.FingerprintLegacyMD5(parsed.PublicKey())
+	if signer.KeyFingerprint() != want {
+		t.Fatalf("KeyFingerprint() = %q, want %q", signer.KeyFingerprint(), want)
+	}
+}
+
+func TestNewPrivateKeySignerInvalidKey(t *testing.T) {
+	_, err := NewPrivateKeySigner(PrivateKeySignerInput{
+		KeyID:       "aa:bb:cc",
+		PrivateKey:  []byte("not a private key"),
+		AccountName: "acmecorp",
+	})
+	if err == nil {
+		t.Fatalf("NewPrivateKeySigner() error = nil, want a parse error")
+	}
+}
+
+func TestPrivateKeySignerSign(t *testing.T) {
+	pemBytes := generateTestPrivateKeyPEM(t)
+
+	signer, err := NewPrivateKeySigner(PrivateKeySignerInput{
+		KeyID:       "aa:bb:cc",
+		PrivateKey:  pemBytes,
+		AccountName: "acmecorp",
+		Username:    "alice",
+	})
+	if err != nil {
+		t.Fatalf("NewPrivateKeySigner() error = %v", err)
+	}
+
+	header, err := signer.Sign("Mon, 02 Jan 2006 15:04:05 GMT")
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	if !strings.HasPrefix(header, `Signature keyId="/acmecorp/users/alice/keys/aa:bb:cc",algorithm="rsa-sha1",signature="`) {
+		t.Fatalf("Sign() = %q, want it to start with the RBAC subuser keyId and rsa-sha1 algorithm", header)
+	}
+	if !strings.HasSuffix(header, `"`) {
+		t.Fatalf("Sign() = %q, want it to end with a closing quote around the signature", header)
+	}
+}
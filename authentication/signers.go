@@ -0,0 +1,11 @@
+package authentication
+
+// Signer is the interface each Triton authentication scheme (SSH agent,
+// raw private key, ...) must implement in order to be accepted by
+// client.New. Sign is called once per attempt of an outgoing request
+// (retries are re-signed with a fresh "date") with the contents of the
+// "date" header and returns a fully formed "Authorization" header value.
+type Signer interface {
+	Sign(dateHeader string) (string, error)
+	KeyFingerprint() string
+}
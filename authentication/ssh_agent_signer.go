@@ -0,0 +1,114 @@
+package authentication
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/hashicorp/errwrap"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// SSHAgentSignerInput represents the information required by the
+// NewSSHAgentSigner constructor in order to construct an
+// authentication.Signer which defers signing to a running ssh-agent.
+type SSHAgentSignerInput struct {
+	// KeyID identifies the agent key to use for signing: either its
+	// colon-separated MD5 fingerprint or its comment in the agent. SHA256
+	// fingerprints are not matched.
+	KeyID string
+
+	// AccountName is the Triton account (login) the signature is made on
+	// behalf of.
+	AccountName string
+
+	// Username, if set, is the RBAC subuser under AccountName that owns
+	// KeyID. When empty the signature is made as the account itself.
+	Username string
+}
+
+// SSHAgentSigner implements the Signer interface and is used to sign
+// requests via a key hosted in a running ssh-agent.
+type SSHAgentSigner struct {
+	formattedKeyFingerprint string
+	keyIdentifier           string
+	agent                   agent.Agent
+	key                     ssh.PublicKey
+	accountName             string
+	userName                string
+}
+
+// NewSSHAgentSigner returns a Signer which connects to a local ssh-agent
+// (via SSH_AUTH_SOCK) and signs requests with the key identified by
+// KeyID, without that key's material ever leaving the agent.
+func NewSSHAgentSigner(input SSHAgentSignerInput) (*SSHAgentSigner, error) {
+	agentSock := os.Getenv("SSH_AUTH_SOCK")
+	if agentSock == "" {
+		return nil, fmt.Errorf("SSH_AUTH_SOCK is required to use the SSH agent signer")
+	}
+
+	conn, err := net.Dial("unix", agentSock)
+	if err != nil {
+		return nil, errwrap.Wrapf("error dialing SSH agent: {{err}}", err)
+	}
+
+	sshAgent := agent.NewClient(conn)
+
+	keys, err := sshAgent.List()
+	if err != nil {
+		return nil, errwrap.Wrapf("error listing keys in SSH agent: {{err}}", err)
+	}
+
+	var matchedKey ssh.PublicKey
+	for _, key := range keys {
+		_, fingerprint := fingerprintFor(key)
+		if fingerprint == input.KeyID || key.Comment == input.KeyID {
+			matchedKey = key
+			break
+		}
+	}
+	if matchedKey == nil {
+		return nil, fmt.Errorf("no key matching %q found in SSH agent", input.KeyID)
+	}
+
+	keyFingerprintMD5, _ := fingerprintFor(matchedKey)
+
+	return &SSHAgentSigner{
+		formattedKeyFingerprint: keyFingerprintMD5,
+		keyIdentifier:           input.KeyID,
+		agent:                   sshAgent,
+		key:                     matchedKey,
+		accountName:             input.AccountName,
+		userName:                input.Username,
+	}, nil
+}
+
+// KeyFingerprint returns the fingerprint of the key this signer was
+// constructed with.
+func (s *SSHAgentSigner) KeyFingerprint() string {
+	return s.formattedKeyFingerprint
+}
+
+// Sign produces an "Authorization" header value for the given "date"
+// header contents, using the key identifier form appropriate for the
+// account (or RBAC subuser) this signer was constructed for.
+func (s *SSHAgentSigner) Sign(dateHeader string) (string, error) {
+	signature, err := s.agent.Sign(s.key, []byte(fmt.Sprintf("date: %s", dateHeader)))
+	if err != nil {
+		return "", errwrap.Wrapf("error signing date header with SSH agent: {{err}}", err)
+	}
+
+	keyIDPath := keyIDForSigner(s.accountName, s.userName, s.keyIdentifier)
+
+	return fmt.Sprintf(authorizationHeaderFormat,
+		keyIDPath,
+		signatureAlgorithmFor(s.key.Type()),
+		base64Encode(signature.Blob),
+	), nil
+}
+
+func fingerprintFor(key ssh.PublicKey) (string, string) {
+	md5Fingerprint := ssh.FingerprintLegacyMD5(key)
+	return md5Fingerprint, md5Fingerprint
+}
@@ -0,0 +1,46 @@
+package authentication
+
+import (
+	"os"
+	"testing"
+)
+
+func TestNewSSHAgentSignerRequiresAgentSocket(t *testing.T) {
+	oldSock, hadSock := os.LookupEnv("SSH_AUTH_SOCK")
+	os.Unsetenv("SSH_AUTH_SOCK")
+	defer func() {
+		if hadSock {
+			os.Setenv("SSH_AUTH_SOCK", oldSock)
+		}
+	}()
+
+	_, err := NewSSHAgentSigner(SSHAgentSignerInput{
+		KeyID:       "aa:bb:cc",
+		AccountName: "acmecorp",
+	})
+	if err == nil {
+		t.Fatalf("NewSSHAgentSigner() error = nil, want an error when SSH_AUTH_SOCK is unset")
+	}
+}
+
+func TestFingerprintForIsStable(t *testing.T) {
+	pemBytes := generateTestPrivateKeyPEM(t)
+
+	signer, err := NewPrivateKeySigner(PrivateKeySignerInput{
+		KeyID:       "aa:bb:cc",
+		PrivateKey:  pemBytes,
+		AccountName: "acmecorp",
+	})
+	if err != nil {
+		t.Fatalf("NewPrivateKeySigner() error = %v", err)
+	}
+
+	md5a, full := fingerprintFor(signer.key.PublicKey())
+	md5b, _ := fingerprintFor(signer.key.PublicKey())
+	if md5a != md5b {
+		t.Fatalf("fingerprintFor() is not stable across calls: %q != %q", md5a, md5b)
+	}
+	if full != md5a {
+		t.Fatalf("fingerprintFor() = (%q, %q), want both return values equal", md5a, full)
+	}
+}
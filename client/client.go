@@ -16,6 +16,7 @@ import (
 
 	"github.com/hashicorp/errwrap"
 	"github.com/joyent/triton-go/authentication"
+	tritonerrors "github.com/joyent/triton-go/errors"
 )
 
 const nilContext = "nil context"
@@ -28,12 +29,30 @@ type Client struct {
 	Authorizers []authentication.Signer
 	APIURL      url.URL
 	AccountName string
+	Username    string
 	Endpoint    string
+
+	// DefaultHeaders are applied to every outgoing request before the
+	// per-request Headers in RequestInput, which take precedence. Set
+	// them with SetHeader.
+	DefaultHeaders http.Header
+
+	retryPolicy RetryPolicy
+}
+
+// RequestSigner is the signing step of the request pipeline. It is
+// satisfied by authentication.Signer so that the default CloudAPI/Manta
+// HTTP Signature scheme can be swapped out (for example for Manta RBAC's
+// "/account/users/user/keys/keyid" keyId form) without duplicating the
+// transport code in Do.
+type RequestSigner interface {
+	Sign(dateHeader string) (string, error)
 }
 
 type Config struct {
 	endpoint    string
 	accountName string
+	username    string
 	signers     []authentication.Signer
 }
 
@@ -48,12 +67,65 @@ func (e ClientError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Code, e.Message)
 }
 
+// Unwrap exposes the *tritonerrors.TritonError matching this error's
+// Code, so callers can use errors.As(err, &tritonErr) to reach the
+// StatusCode, or the tritonerrors.Is* predicate helpers (which unwrap a
+// further level to the sentinel TritonError.Cause) instead of
+// string-matching on Code/Message.
+func (e ClientError) Unwrap() error {
+	return tritonerrors.New(e.StatusCode, e.Code, e.Message)
+}
+
+// ClientOption configures optional aspects of a Client constructed by
+// New, such as its signers, HTTP client, RBAC subuser, or retry policy.
+type ClientOption func(*Client)
+
+// WithSigners appends one or more authentication.Signer to the Client.
+// At least one signer must end up configured, either via WithSigners or
+// the SDC_KEY_ID SSH-agent fallback in New.
+func WithSigners(signers ...authentication.Signer) ClientOption {
+	return func(c *Client) {
+		for _, signer := range signers {
+			if signer != nil {
+				c.Authorizers = append(c.Authorizers, signer)
+			}
+		}
+	}
+}
+
+// WithUsername sets the RBAC subuser making requests on behalf of the
+// account. When unset and New constructs its default SSH agent signer
+// (no WithSigners, SDC_KEY_ID set), New falls back to the SDC_USER,
+// TRITON_USER and MANTA_SUBUSER environment variables, in that order.
+func WithUsername(username string) ClientOption {
+	return func(c *Client) {
+		c.Username = username
+	}
+}
+
+// WithHTTPClient overrides the *http.Client used for requests. New still
+// wraps its Transport with the configured RetryPolicy; to disable
+// retries entirely, combine this with WithRetryPolicy(NoRetryPolicy()).
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithRetryPolicy overrides the default retry/backoff policy applied to
+// every request. Pass NoRetryPolicy() to disable retries.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
 // New is used to construct a Client in order to make API
 // requests to the Triton API.
 //
-// At least one signer must be provided - example signers include
-// authentication.PrivateKeySigner and authentication.SSHAgentSigner.
-func New(endpoint string, accountName string, signers ...authentication.Signer) (*Client, error) {
+// At least one signer must be provided via WithSigners - example signers
+// include authentication.PrivateKeySigner and authentication.SSHAgentSigner.
+func New(endpoint string, accountName string, options ...ClientOption) (*Client, error) {
 	apiURL, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, errwrap.Wrapf("invalid endpoint: {{err}}", err)
@@ -63,45 +135,62 @@ func New(endpoint string, accountName string, signers ...authentication.Signer)
 		return nil, errors.New("account name can not be empty")
 	}
 
-	httpClient := &http.Client{
-		Transport:     httpTransport(false),
-		CheckRedirect: doNotFollowRedirects,
-	}
-
 	newClient := &Client{
-		HTTPClient:  httpClient,
-		Authorizers: signers,
+		HTTPClient: &http.Client{
+			Transport:     httpTransport(false),
+			CheckRedirect: doNotFollowRedirects,
+		},
 		APIURL:      *apiURL,
 		AccountName: accountName,
 		Endpoint:    endpoint,
+		retryPolicy: DefaultRetryPolicy(),
 	}
 
-	var authorizers []authentication.Signer
-	for _, key := range signers {
-		if key != nil {
-			authorizers = append(authorizers, key)
-		}
+	for _, option := range options {
+		option(newClient)
 	}
 
-	// Default to constructing an SSHAgentSigner if there are no other signers
-	// passed into NewClient and there's an SDC_KEY_ID value available in the
+	// Default to constructing an SSHAgentSigner if no signers were passed
+	// in via WithSigners and there's an SDC_KEY_ID value available in the
 	// user environ.
-	if len(authorizers) == 0 {
+	if len(newClient.Authorizers) == 0 {
 		keyID := os.Getenv("SDC_KEY_ID")
 		if len(keyID) != 0 {
-			keySigner, err := authentication.NewSSHAgentSigner(keyID, accountName)
+			if newClient.Username == "" {
+				newClient.Username = defaultSubuserFromEnviron()
+			}
+
+			keySigner, err := authentication.NewSSHAgentSigner(authentication.SSHAgentSignerInput{
+				KeyID:       keyID,
+				AccountName: accountName,
+				Username:    newClient.Username,
+			})
 			if err != nil {
 				return nil, errwrap.Wrapf("Problem initializing NewSSHAgentSigner: {{err}}", err)
 			}
-			newClient.Authorizers = append(authorizers, keySigner)
+			newClient.Authorizers = append(newClient.Authorizers, keySigner)
 		} else {
 			return nil, MissingKeyIdError
 		}
 	}
 
+	newClient.HTTPClient.Transport = newRetryTransport(newClient.HTTPClient.Transport, newClient.retryPolicy)
+
 	return newClient, nil
 }
 
+// defaultSubuserFromEnviron looks for an RBAC subuser name in the
+// environment variables recognized by the various Triton clients, in
+// order of precedence.
+func defaultSubuserFromEnviron() string {
+	for _, envVar := range []string{"SDC_USER", "TRITON_USER", "MANTA_SUBUSER"} {
+		if user := os.Getenv(envVar); user != "" {
+			return user
+		}
+	}
+	return ""
+}
+
 // InsecureSkipTLSVerify turns off TLS verification for the client connection. This
 // allows connection to an endpoint with a certificate which was signed by a non-
 // trusted CA, such as self-signed certificates. This can be useful when connecting
@@ -114,6 +203,18 @@ func (c *Client) InsecureSkipTLSVerify() {
 	c.HTTPClient.Transport = httpTransport(true)
 }
 
+// SetHeader installs a default header applied to every request made by
+// this Client. Per-request Headers passed to Do (via RequestInput) or to
+// ExecuteRequestStream override a default set with the same key. This is the mechanism for things like pinning
+// Accept-Version, tagging requests with x-request-id, or setting Manta
+// m-* metadata/If-Match headers that apply to every call.
+func (c *Client) SetHeader(key, value string) {
+	if c.DefaultHeaders == nil {
+		c.DefaultHeaders = http.Header{}
+	}
+	c.DefaultHeaders.Set(key, value)
+}
+
 func httpTransport(insecureSkipTLSVerify bool) *http.Transport {
 	return &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
@@ -153,10 +254,28 @@ func (c *Client) DecodeError(statusCode int, body io.Reader) error {
 
 // -----------------------------------------------------------------------------
 
-func (c *Client) ExecuteRequestURIParams(ctx context.Context, method, path string, body interface{}, query *url.Values) (io.ReadCloser, error) {
+// RequestInput describes a single call to Do. Query and Headers are
+// both optional; Headers are merged over Client.DefaultHeaders and take
+// precedence on key collisions. RawResponse skips the 2xx status check
+// and DecodeError handling, returning the raw *http.Response so callers
+// that need the status code or non-JSON bodies (Manta object downloads,
+// for instance) can handle it themselves.
+type RequestInput struct {
+	Method      string
+	Path        string
+	Query       *url.Values
+	Headers     http.Header
+	Body        interface{}
+	RawResponse bool
+}
+
+// Do is the single entry point the ExecuteRequest* helpers below build
+// on. It constructs the HTTP request, applies default and per-request
+// headers, signs the request via RequestSigner, and executes it.
+func (c *Client) Do(ctx context.Context, input RequestInput) (*http.Response, error) {
 	var requestBody io.ReadSeeker
-	if body != nil {
-		marshaled, err := json.MarshalIndent(body, "", "    ")
+	if input.Body != nil {
+		marshaled, err := json.MarshalIndent(input.Body, "", "    ")
 		if err != nil {
 			return nil, err
 		}
@@ -164,90 +283,107 @@ func (c *Client) ExecuteRequestURIParams(ctx context.Context, method, path strin
 	}
 
 	endpoint := c.APIURL
-	endpoint.Path = path
-	if query != nil {
-		endpoint.RawQuery = query.Encode()
+	endpoint.Path = input.Path
+	if input.Query != nil {
+		endpoint.RawQuery = input.Query.Encode()
 	}
 
-	req, err := http.NewRequest(method, endpoint.String(), requestBody)
+	req, err := http.NewRequest(input.Method, endpoint.String(), requestBody)
 	if err != nil {
 		return nil, errwrap.Wrapf("Error constructing HTTP request: {{err}}", err)
 	}
 
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Version", "8")
+	req.Header.Set("User-Agent", "triton-go Client API")
+	if input.Body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	for key, values := range c.DefaultHeaders {
+		req.Header[key] = values
+	}
+	for key, values := range input.Headers {
+		req.Header[key] = values
+	}
+
 	dateHeader := time.Now().UTC().Format(time.RFC1123)
 	req.Header.Set("date", dateHeader)
 
 	// NewClient ensures there's always an authorizer (unless this is called
 	// outside that constructor).
-	authHeader, err := c.Authorizers[0].Sign(dateHeader)
+	authHeader, err := c.signRequest(c.Authorizers[0], dateHeader)
 	if err != nil {
-		return nil, errwrap.Wrapf("Error signing HTTP request: {{err}}", err)
+		return nil, err
 	}
 	req.Header.Set("Authorization", authHeader)
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Accept-Version", "8")
-	req.Header.Set("User-Agent", "triton-go Client API")
 
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
+	// The retry transport re-signs the Date/Authorization headers before
+	// each retry attempt, since HTTP Signature auth binds to the date
+	// header and a stale Authorization header would fail verification.
+	ctx = withResigner(ctx, func(req *http.Request, dateHeader string) error {
+		authHeader, err := c.signRequest(c.Authorizers[0], dateHeader)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("date", dateHeader)
+		req.Header.Set("Authorization", authHeader)
+		return nil
+	})
 
 	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
 	if err != nil {
 		return nil, errwrap.Wrapf("Error executing HTTP request: {{err}}", err)
 	}
 
+	if input.RawResponse {
+		return resp, nil
+	}
+
 	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
-		return resp.Body, nil
+		return resp, nil
 	}
 
+	defer resp.Body.Close()
 	return nil, c.DecodeError(resp.StatusCode, resp.Body)
 }
 
-func (c *Client) ExecuteRequest(ctx context.Context, method, path string, body interface{}) (io.ReadCloser, error) {
-	return c.ExecuteRequestURIParams(ctx, method, path, body, nil)
-}
-
-func (c *Client) ExecuteRequestRaw(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
-	var requestBody io.ReadSeeker
-	if body != nil {
-		marshaled, err := json.MarshalIndent(body, "", "    ")
-		if err != nil {
-			return nil, err
-		}
-		requestBody = bytes.NewReader(marshaled)
-	}
-
-	endpoint := c.APIURL
-	endpoint.Path = path
-
-	req, err := http.NewRequest(method, endpoint.String(), requestBody)
+// signRequest invokes the signing step of the pipeline. It is factored
+// out of Do so alternate RequestSigner implementations (Manta RBAC's
+// subuser keyId form, a future signature scheme, ...) only need to
+// implement Sign, never touch request construction or transport.
+func (c *Client) signRequest(signer RequestSigner, dateHeader string) (string, error) {
+	authHeader, err := signer.Sign(dateHeader)
 	if err != nil {
-		return nil, errwrap.Wrapf("Error constructing HTTP request: {{err}}", err)
+		return "", errwrap.Wrapf("Error signing HTTP request: {{err}}", err)
 	}
+	return authHeader, nil
+}
 
-	dateHeader := time.Now().UTC().Format(time.RFC1123)
-	req.Header.Set("date", dateHeader)
-
-	// NewClient ensures there's always an authorizer (unless this is called
-	// outside that constructor).
-	authHeader, err := c.Authorizers[0].Sign(dateHeader)
+func (c *Client) ExecuteRequestURIParams(ctx context.Context, method, path string, body interface{}, query *url.Values) (io.ReadCloser, error) {
+	resp, err := c.Do(ctx, RequestInput{
+		Method: method,
+		Path:   path,
+		Body:   body,
+		Query:  query,
+	})
 	if err != nil {
-		return nil, errwrap.Wrapf("Error signing HTTP request: {{err}}", err)
+		return nil, err
 	}
-	req.Header.Set("Authorization", authHeader)
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Accept-Version", "8")
-	req.Header.Set("User-Agent", "triton-go c API")
 
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
+	return resp.Body, nil
+}
 
-	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
-	if err != nil {
-		return nil, errwrap.Wrapf("Error executing HTTP request: {{err}}", err)
-	}
+func (c *Client) ExecuteRequest(ctx context.Context, method, path string, body interface{}) (io.ReadCloser, error) {
+	return c.ExecuteRequestURIParams(ctx, method, path, body, nil)
+}
 
-	return resp, nil
+func (c *Client) ExecuteRequestRaw(ctx context.Context, method, path string, body interface{}, query *url.Values) (*http.Response, error) {
+	return c.Do(ctx, RequestInput{
+		Method:      method,
+		Path:        path,
+		Body:        body,
+		Query:       query,
+		RawResponse: true,
+	})
 }
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	tritonerrors "github.com/joyent/triton-go/errors"
+)
+
+func TestClientErrorUnwrapsToTritonError(t *testing.T) {
+	clientErr := ClientError{
+		StatusCode: http.StatusNotFound,
+		Code:       "ResourceNotFound",
+		Message:    "boom",
+	}
+
+	var tritonErr *tritonerrors.TritonError
+	if !errors.As(error(clientErr), &tritonErr) {
+		t.Fatalf("errors.As(clientErr, &tritonErr) = false, want true")
+	}
+	if tritonErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("tritonErr.StatusCode = %d, want %d", tritonErr.StatusCode, http.StatusNotFound)
+	}
+
+	if !tritonerrors.IsResourceNotFound(clientErr) {
+		t.Fatalf("tritonerrors.IsResourceNotFound(clientErr) = false, want true")
+	}
+}
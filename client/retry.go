@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures the retry/backoff behavior of the transport
+// installed by New. The zero value disables retries, the same as
+// NoRetryPolicy; construct one with DefaultRetryPolicy or NoRetryPolicy
+// and adjust fields as needed, then pass it to WithRetryPolicy.
+type RetryPolicy struct {
+	// MaxRetries is the number of additional attempts made after the
+	// initial request. A value of 0 disables retries entirely.
+	MaxRetries int
+
+	// BaseDelay is the backoff delay before the first retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the computed backoff delay, before jitter is applied.
+	MaxDelay time.Duration
+
+	// Jitter randomizes each computed delay within +/-50%, to avoid
+	// retry storms when many clients back off in lockstep.
+	Jitter bool
+
+	// IdempotentMethods lists the HTTP methods that are safe to retry
+	// whenever the response itself indicates a transient failure (a
+	// retryable status code). Non-idempotent methods (typically POST)
+	// are only ever retried on a network-level error, and only when
+	// httptrace proves the request was never fully written to the
+	// connection - see the wroteRequest handling in RoundTrip.
+	IdempotentMethods map[string]bool
+}
+
+// DefaultRetryPolicy retries GET/HEAD/PUT/DELETE/OPTIONS requests up to
+// 3 times with exponential backoff between 500ms and 30s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   30 * time.Second,
+		Jitter:     true,
+		IdempotentMethods: map[string]bool{
+			http.MethodGet:     true,
+			http.MethodHead:    true,
+			http.MethodPut:     true,
+			http.MethodDelete:  true,
+			http.MethodOptions: true,
+		},
+	}
+}
+
+// NoRetryPolicy disables the retry layer; every request is attempted
+// exactly once.
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxRetries: 0}
+}
+
+func (p RetryPolicy) isIdempotent(method string) bool {
+	return p.IdempotentMethods[method]
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << uint(attempt)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter {
+		delay = time.Duration(float64(delay) * (0.5 + rand.Float64()))
+	}
+	return delay
+}
+
+// resigner is invoked by retryTransport before each retry attempt so
+// that the Date and Authorization headers are recomputed: HTTP
+// Signature auth binds to the date header, so replaying the original
+// Authorization header on a retry issued more than a few minutes later
+// would fail signature verification on the server.
+type resigner func(req *http.Request, dateHeader string) error
+
+type resignerContextKey struct{}
+
+func withResigner(ctx context.Context, resign resigner) context.Context {
+	return context.WithValue(ctx, resignerContextKey{}, resign)
+}
+
+func resignerFromContext(ctx context.Context) (resigner, bool) {
+	resign, ok := ctx.Value(resignerContextKey{}).(resigner)
+	return resign, ok
+}
+
+// retryTransport wraps an http.RoundTripper with RetryPolicy. It is
+// installed transparently by New, so callers needing a custom transport
+// should set it via WithHTTPClient and let New wrap it rather than
+// replacing c.HTTPClient.Transport directly.
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+func newRetryTransport(next http.RoundTripper, policy RetryPolicy) *retryTransport {
+	return &retryTransport{next: next, policy: policy}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var retryAfter time.Duration
+
+	// A request body we can't replay (no GetBody, e.g. a streamed Manta
+	// upload reading from a non-seekable io.Reader) must never be
+	// retried: the first attempt may have already consumed part of it.
+	retryable := req.Body == nil || req.GetBody != nil
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			wait := retryAfter
+			if wait == 0 {
+				wait = t.policy.backoff(attempt - 1)
+			}
+
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(wait):
+			}
+
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = body
+			}
+
+			if resign, ok := resignerFromContext(req.Context()); ok {
+				dateHeader := time.Now().UTC().Format(time.RFC1123)
+				if err := resign(req, dateHeader); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		attemptReq, wroteRequest := withWroteRequestTrace(req)
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if err != nil {
+			// A network-level error is only provably safe to retry for a
+			// non-idempotent method (typically POST) when httptrace
+			// tells us the request was never fully written to the
+			// connection - then we know the server never saw it. Once
+			// WroteRequest has fired without error, the failure happened
+			// while waiting on (or reading) the response, and the server
+			// may already have processed the request; fall back to the
+			// idempotency policy in that case, same as for a response
+			// status.
+			safeForNonIdempotent := !*wroteRequest
+			if !retryable || attempt >= t.policy.MaxRetries || !(t.policy.isIdempotent(req.Method) || safeForNonIdempotent) {
+				return nil, err
+			}
+			retryAfter = 0
+			continue
+		}
+
+		if !retryable || attempt >= t.policy.MaxRetries || !shouldRetryStatus(resp.StatusCode) || !t.policy.isIdempotent(req.Method) {
+			return resp, nil
+		}
+
+		retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		resp.Body.Close()
+	}
+}
+
+// withWroteRequestTrace returns a shallow copy of req carrying an
+// httptrace.ClientTrace that records whether the request (headers and
+// body) was fully written to the connection before any error. The
+// returned bool is only safe to read after the RoundTrip call that
+// consumes the returned request has returned.
+func withWroteRequestTrace(req *http.Request) (*http.Request, *bool) {
+	wrote := new(bool)
+	trace := &httptrace.ClientTrace{
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			*wrote = info.Err == nil
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), wrote
+}
+
+func shouldRetryStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptrace"
+	"testing"
+	"time"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		want    time.Duration
+	}{
+		{
+			name:    "first attempt uses base delay",
+			policy:  RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute},
+			attempt: 0,
+			want:    time.Second,
+		},
+		{
+			name:    "doubles each attempt",
+			policy:  RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute},
+			attempt: 2,
+			want:    4 * time.Second,
+		},
+		{
+			name:    "caps at MaxDelay",
+			policy:  RetryPolicy{BaseDelay: time.Second, MaxDelay: 3 * time.Second},
+			attempt: 5,
+			want:    3 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.backoff(tt.attempt)
+			if got != tt.want {
+				t.Fatalf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffJitterStaysInRange(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute, Jitter: true}
+
+	for i := 0; i < 50; i++ {
+		got := policy.backoff(1)
+		if got < time.Second || got > 3*time.Second {
+			t.Fatalf("backoff(1) with jitter = %v, want within [1s, 3s]", got)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"empty value", "", 0},
+		{"seconds", "5", 5 * time.Second},
+		{"zero seconds", "0", 0},
+		{"not a number or date", "not-a-valid-value", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseRetryAfter(tt.value)
+			if got != tt.want {
+				t.Fatalf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfterHTTPDate(t *testing.T) {
+	future := time.Now().Add(10 * time.Second).UTC()
+	value := future.Format(http.TimeFormat)
+
+	got := parseRetryAfter(value)
+	if got <= 0 || got > 11*time.Second {
+		t.Fatalf("parseRetryAfter(%q) = %v, want roughly 10s", value, got)
+	}
+}
+
+// TestRoundTripRetriesNetworkErrorForNonIdempotentMethod locks in the
+// fix where a POST that fails with a network-level error before the
+// request was ever written to the connection (so the server provably
+// never saw it) is retried even though POST is not in
+// RetryPolicy.IdempotentMethods.
+func TestRoundTripRetriesNetworkErrorForNonIdempotentMethod(t *testing.T) {
+	attempts := 0
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	policy := RetryPolicy{
+		MaxRetries:        2,
+		BaseDelay:         time.Millisecond,
+		MaxDelay:          time.Millisecond,
+		IdempotentMethods: map[string]bool{},
+	}
+	transport := newRetryTransport(next, policy)
+
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/uploads/123/commit", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v, want nil", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("RoundTrip() status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+}
+
+// TestRoundTripDoesNotRetryNonIdempotentMethodAfterRequestWasWritten
+// ensures the fix above is scoped to failures that happen before the
+// request reaches the wire: once httptrace reports WroteRequest with no
+// error, the server may have already processed the POST, so a
+// subsequent network error (e.g. the connection resetting while reading
+// the response) must not be retried.
+func TestRoundTripDoesNotRetryNonIdempotentMethodAfterRequestWasWritten(t *testing.T) {
+	attempts := 0
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if trace := httptrace.ContextClientTrace(req.Context()); trace != nil && trace.WroteRequest != nil {
+			trace.WroteRequest(httptrace.WroteRequestInfo{})
+		}
+		return nil, errors.New("connection reset by peer while waiting for response")
+	})
+
+	policy := RetryPolicy{
+		MaxRetries:        2,
+		BaseDelay:         time.Millisecond,
+		MaxDelay:          time.Millisecond,
+		IdempotentMethods: map[string]bool{},
+	}
+	transport := newRetryTransport(next, policy)
+
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/uploads/123/commit", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+
+	_, err = transport.RoundTrip(req)
+	if err == nil {
+		t.Fatalf("RoundTrip() error = nil, want the network error surfaced")
+	}
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1 (no retry once the request was written)", attempts)
+	}
+}
+
+// TestRoundTripDoesNotRetryNonIdempotentMethodOnErrorStatus ensures the
+// fix above is scoped to network errors only: a POST that gets a
+// retryable HTTP status back (503, say) is still not retried, since
+// unlike a network error we know the request reached the server.
+func TestRoundTripDoesNotRetryNonIdempotentMethodOnErrorStatus(t *testing.T) {
+	attempts := 0
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody, Header: http.Header{}}, nil
+	})
+
+	policy := RetryPolicy{
+		MaxRetries:        2,
+		BaseDelay:         time.Millisecond,
+		MaxDelay:          time.Millisecond,
+		IdempotentMethods: map[string]bool{},
+	}
+	transport := newRetryTransport(next, policy)
+
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/uploads/123/commit", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v, want nil", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("RoundTrip() status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1 (no retry on error status for non-idempotent method)", attempts)
+	}
+}
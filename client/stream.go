@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+)
+
+// ExecuteRequestStream behaves like Do, but streams body directly to
+// the transport instead of buffering it through json.Marshal, so large
+// Manta object uploads don't have to be held in memory.
+//
+// When body implements io.Seeker, ExecuteRequestStream hashes it up
+// front to set Content-MD5 and rewinds it for the real send; the
+// retry transport can also rewind it before a retry attempt. When body
+// does not implement io.Seeker, Content-MD5 is left unset and the
+// request is attempted exactly once, since a partially-consumed,
+// non-seekable reader can't be safely replayed.
+func (c *Client) ExecuteRequestStream(ctx context.Context, method, path string, body io.Reader, contentType string, contentLength int64, headers http.Header) (*http.Response, error) {
+	endpoint := c.APIURL
+	endpoint.Path = path
+
+	seeker, seekable := body.(io.Seeker)
+
+	var contentMD5 string
+	if seekable {
+		hash := md5.New()
+		if _, err := io.Copy(hash, body); err != nil {
+			return nil, errwrap.Wrapf("Error hashing request body: {{err}}", err)
+		}
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return nil, errwrap.Wrapf("Error rewinding request body: {{err}}", err)
+		}
+		contentMD5 = base64.StdEncoding.EncodeToString(hash.Sum(nil))
+	}
+
+	req, err := http.NewRequest(method, endpoint.String(), body)
+	if err != nil {
+		return nil, errwrap.Wrapf("Error constructing HTTP request: {{err}}", err)
+	}
+	req.ContentLength = contentLength
+
+	if seekable {
+		req.GetBody = func() (io.ReadCloser, error) {
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return io.NopCloser(body), nil
+		}
+	}
+
+	for key, values := range c.DefaultHeaders {
+		req.Header[key] = values
+	}
+	for key, values := range headers {
+		req.Header[key] = values
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	if contentMD5 != "" {
+		req.Header.Set("Content-MD5", contentMD5)
+	}
+
+	dateHeader := time.Now().UTC().Format(time.RFC1123)
+	req.Header.Set("date", dateHeader)
+
+	authHeader, err := c.signRequest(c.Authorizers[0], dateHeader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", authHeader)
+
+	ctx = withResigner(ctx, func(req *http.Request, dateHeader string) error {
+		authHeader, err := c.signRequest(c.Authorizers[0], dateHeader)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("date", dateHeader)
+		req.Header.Set("Authorization", authHeader)
+		return nil
+	})
+
+	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errwrap.Wrapf("Error executing HTTP request: {{err}}", err)
+	}
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	return nil, c.DecodeError(resp.StatusCode, resp.Body)
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/joyent/triton-go/authentication"
+)
+
+type fakeSigner struct{}
+
+func (fakeSigner) Sign(dateHeader string) (string, error) {
+	return `Signature keyId="/acmecorp/keys/aa:bb:cc",algorithm="rsa-sha1",signature="fake"`, nil
+}
+
+func (fakeSigner) KeyFingerprint() string {
+	return "aa:bb:cc"
+}
+
+func newTestClient(t *testing.T, transport roundTripperFunc) *Client {
+	t.Helper()
+
+	apiURL, err := url.Parse("https://us-east.manta.joyent.com")
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	return &Client{
+		HTTPClient:  &http.Client{Transport: transport},
+		Authorizers: []authentication.Signer{fakeSigner{}},
+		APIURL:      *apiURL,
+		AccountName: "acmecorp",
+	}
+}
+
+func TestExecuteRequestStreamSetsContentMD5ForSeekableBody(t *testing.T) {
+	content := []byte("part contents")
+	hash := md5.Sum(content)
+	wantMD5 := base64.StdEncoding.EncodeToString(hash[:])
+
+	var gotMD5 string
+	var gotBody []byte
+	c := newTestClient(t, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		gotMD5 = req.Header.Get("Content-MD5")
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		gotBody = body
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}, nil
+	}))
+
+	body := bytes.NewReader(content)
+	resp, err := c.ExecuteRequestStream(context.Background(), http.MethodPut, "/acmecorp/uploads/1/0", body, "application/octet-stream", int64(len(content)), nil)
+	if err != nil {
+		t.Fatalf("ExecuteRequestStream() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if gotMD5 != wantMD5 {
+		t.Fatalf("Content-MD5 = %q, want %q", gotMD5, wantMD5)
+	}
+	if !bytes.Equal(gotBody, content) {
+		t.Fatalf("request body = %q, want %q", gotBody, content)
+	}
+}
+
+func TestExecuteRequestStreamSkipsContentMD5ForNonSeekableBody(t *testing.T) {
+	var gotMD5Set bool
+	c := newTestClient(t, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		_, gotMD5Set = req.Header["Content-Md5"]
+		io.Copy(io.Discard, req.Body)
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}, nil
+	}))
+
+	body := io.NopCloser(bytes.NewReader([]byte("part contents")))
+	resp, err := c.ExecuteRequestStream(context.Background(), http.MethodPut, "/acmecorp/uploads/1/0", body, "application/octet-stream", 13, nil)
+	if err != nil {
+		t.Fatalf("ExecuteRequestStream() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if gotMD5Set {
+		t.Fatalf("Content-MD5 header set for a non-seekable body, want unset")
+	}
+}
+
+func TestExecuteRequestStreamGetBodyRewindsSeekableBody(t *testing.T) {
+	content := []byte("part contents")
+
+	var getBody func() (io.ReadCloser, error)
+	c := newTestClient(t, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		getBody = req.GetBody
+		io.Copy(io.Discard, req.Body)
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}, nil
+	}))
+
+	body := bytes.NewReader(content)
+	resp, err := c.ExecuteRequestStream(context.Background(), http.MethodPut, "/acmecorp/uploads/1/0", body, "application/octet-stream", int64(len(content)), nil)
+	if err != nil {
+		t.Fatalf("ExecuteRequestStream() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if getBody == nil {
+		t.Fatalf("req.GetBody = nil, want a rewind function for a seekable body")
+	}
+
+	rewound, err := getBody()
+	if err != nil {
+		t.Fatalf("GetBody() error = %v", err)
+	}
+	got, err := io.ReadAll(rewound)
+	if err != nil {
+		t.Fatalf("ReadAll(GetBody()) error = %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("GetBody() = %q, want %q", got, content)
+	}
+}
@@ -0,0 +1,175 @@
+// Package errors wraps the error codes returned by the Triton CloudAPI
+// and Manta APIs into typed sentinel errors, so that callers can use
+// errors.As/errwrap.Contains and the Is* predicate helpers below
+// instead of string-matching on ClientError.Code or ClientError.Message.
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// TritonError is the typed form of client.ClientError. DecodeError still
+// returns a *client.ClientError with Code/Message/StatusCode populated
+// exactly as before; its Unwrap method builds a TritonError from them via
+// New, with Cause set to one of the sentinel errors below, so existing
+// callers that only look at Code/Message keep working unmodified.
+type TritonError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Cause      error
+}
+
+// Error implements the error interface.
+func (e *TritonError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap allows errors.Is/errors.As to see through TritonError to the
+// sentinel it was constructed with.
+func (e *TritonError) Unwrap() error {
+	return e.Cause
+}
+
+// Sentinel errors corresponding to the CloudAPI/Manta error codes
+// documented at https://apidocs.tritondatacenter.com/cloudapi/#errors
+var (
+	ErrResourceNotFound  = errors.New("triton: resource not found")
+	ErrInvalidArgument   = errors.New("triton: invalid argument")
+	ErrInUseError        = errors.New("triton: resource in use")
+	ErrAuthSchemeError   = errors.New("triton: invalid authentication scheme")
+	ErrAuthFailed        = errors.New("triton: authentication failed")
+	ErrBadRequest        = errors.New("triton: bad request")
+	ErrInvalidCredential = errors.New("triton: invalid credentials")
+	ErrInvalidHeader     = errors.New("triton: invalid header")
+	ErrInvalidVersion    = errors.New("triton: invalid version")
+	ErrMissingParameter  = errors.New("triton: missing parameter")
+	ErrNotAuthorized     = errors.New("triton: not authorized")
+	ErrRequestThrottled  = errors.New("triton: request throttled")
+	ErrRequestTooLarge   = errors.New("triton: request too large")
+	ErrRequestMoved      = errors.New("triton: request moved")
+	ErrResourceExists    = errors.New("triton: resource already exists")
+	ErrInternal          = errors.New("triton: internal error")
+	ErrUnknown           = errors.New("triton: unknown error")
+)
+
+// codeToSentinel maps the Code field CloudAPI/Manta put in their JSON
+// error bodies to the sentinel errors above.
+var codeToSentinel = map[string]error{
+	"ResourceNotFound":    ErrResourceNotFound,
+	"VolumeNotFound":      ErrResourceNotFound,
+	"KeyNotFound":         ErrResourceNotFound,
+	"InvalidArgument":     ErrInvalidArgument,
+	"InvalidParameter":    ErrInvalidArgument,
+	"InUseError":          ErrInUseError,
+	"InvalidAuthScheme":   ErrAuthSchemeError,
+	"AuthSchemeError":     ErrAuthSchemeError,
+	"AuthorizationFailed": ErrAuthFailed,
+	"InvalidCredentials":  ErrInvalidCredential,
+	"BadRequest":          ErrBadRequest,
+	"InvalidHeader":       ErrInvalidHeader,
+	"InvalidVersion":      ErrInvalidVersion,
+	"MissingParameter":    ErrMissingParameter,
+	"NotAuthorized":       ErrNotAuthorized,
+	"ThrottledError":      ErrRequestThrottled,
+	"RequestTooLarge":     ErrRequestTooLarge,
+	"RequestMoved":        ErrRequestMoved,
+	"ResourceExists":      ErrResourceExists,
+	"InternalError":       ErrInternal,
+}
+
+// New constructs a TritonError for the given status code, API error
+// code and message, resolving Cause from the known CloudAPI/Manta error
+// codes (falling back to ErrInternal/ErrUnknown for unrecognized ones).
+func New(statusCode int, code, message string) *TritonError {
+	cause, ok := codeToSentinel[code]
+	if !ok {
+		if statusCode >= http.StatusInternalServerError {
+			cause = ErrInternal
+		} else {
+			cause = ErrUnknown
+		}
+	}
+
+	return &TritonError{
+		StatusCode: statusCode,
+		Code:       code,
+		Message:    message,
+		Cause:      cause,
+	}
+}
+
+// IsSpecificStatusCode reports whether err was decoded from an HTTP
+// response with the given status code.
+func IsSpecificStatusCode(err error, statusCode int) bool {
+	var tritonErr *TritonError
+	if errors.As(err, &tritonErr) {
+		return tritonErr.StatusCode == statusCode
+	}
+	return false
+}
+
+func is(err error, target error) bool {
+	return errors.Is(err, target)
+}
+
+// IsResourceNotFound reports whether err represents a missing resource
+// (CloudAPI ResourceNotFound/KeyNotFound, Manta ResourceNotFound).
+func IsResourceNotFound(err error) bool { return is(err, ErrResourceNotFound) }
+
+// IsInvalidArgument reports whether err represents an invalid or
+// malformed request argument.
+func IsInvalidArgument(err error) bool { return is(err, ErrInvalidArgument) }
+
+// IsInUseError reports whether err represents a resource that cannot be
+// modified or removed because it is currently in use.
+func IsInUseError(err error) bool { return is(err, ErrInUseError) }
+
+// IsAuthSchemeError reports whether err represents an unsupported or
+// malformed authentication scheme.
+func IsAuthSchemeError(err error) bool { return is(err, ErrAuthSchemeError) }
+
+// IsAuthFailed reports whether err represents a failed authentication
+// attempt (bad signature, unknown key, ...).
+func IsAuthFailed(err error) bool { return is(err, ErrAuthFailed) }
+
+// IsBadRequest reports whether err represents a generically malformed
+// request.
+func IsBadRequest(err error) bool { return is(err, ErrBadRequest) }
+
+// IsInvalidCredential reports whether err represents invalid
+// credentials being supplied for authentication.
+func IsInvalidCredential(err error) bool { return is(err, ErrInvalidCredential) }
+
+// IsInvalidHeader reports whether err represents an invalid header on
+// the request (for example a malformed Authorization header).
+func IsInvalidHeader(err error) bool { return is(err, ErrInvalidHeader) }
+
+// IsInvalidVersion reports whether err represents an unsupported
+// Accept-Version being requested.
+func IsInvalidVersion(err error) bool { return is(err, ErrInvalidVersion) }
+
+// IsMissingParameter reports whether err represents a required
+// parameter that was omitted from the request.
+func IsMissingParameter(err error) bool { return is(err, ErrMissingParameter) }
+
+// IsNotAuthorized reports whether err represents a request that was
+// authenticated but not authorized to perform the action.
+func IsNotAuthorized(err error) bool { return is(err, ErrNotAuthorized) }
+
+// IsRequestThrottled reports whether err represents a request that was
+// rejected due to rate limiting.
+func IsRequestThrottled(err error) bool { return is(err, ErrRequestThrottled) }
+
+// IsRequestTooLarge reports whether err represents a request body that
+// exceeded the API's size limit.
+func IsRequestTooLarge(err error) bool { return is(err, ErrRequestTooLarge) }
+
+// IsResourceExists reports whether err represents an attempt to create
+// a resource that already exists.
+func IsResourceExists(err error) bool { return is(err, ErrResourceExists) }
+
+// IsInternal reports whether err represents an internal server error.
+func IsInternal(err error) bool { return is(err, ErrInternal) }
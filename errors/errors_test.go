@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestNewResolvesCause(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		code       string
+		wantCause  error
+	}{
+		{"resource not found", http.StatusNotFound, "ResourceNotFound", ErrResourceNotFound},
+		{"key not found", http.StatusNotFound, "KeyNotFound", ErrResourceNotFound},
+		{"invalid argument", http.StatusBadRequest, "InvalidArgument", ErrInvalidArgument},
+		{"in use", http.StatusConflict, "InUseError", ErrInUseError},
+		{"auth scheme", http.StatusUnauthorized, "InvalidAuthScheme", ErrAuthSchemeError},
+		{"auth failed", http.StatusUnauthorized, "AuthorizationFailed", ErrAuthFailed},
+		{"bad request", http.StatusBadRequest, "BadRequest", ErrBadRequest},
+		{"internal error code", http.StatusInternalServerError, "InternalError", ErrInternal},
+		{"unrecognized 5xx falls back to internal", http.StatusInternalServerError, "SomethingNew", ErrInternal},
+		{"unrecognized 4xx falls back to unknown", http.StatusBadRequest, "SomethingNew", ErrUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(tt.statusCode, tt.code, "boom")
+			if !errors.Is(err, tt.wantCause) {
+				t.Fatalf("New(%d, %q, ...) cause = %v, want %v", tt.statusCode, tt.code, err.Cause, tt.wantCause)
+			}
+		})
+	}
+}
+
+func TestIsPredicates(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		is   func(error) bool
+		want bool
+	}{
+		{"IsResourceNotFound matches", "ResourceNotFound", IsResourceNotFound, true},
+		{"IsResourceNotFound rejects other code", "InvalidArgument", IsResourceNotFound, false},
+		{"IsInvalidArgument matches", "InvalidArgument", IsInvalidArgument, true},
+		{"IsInUseError matches", "InUseError", IsInUseError, true},
+		{"IsAuthSchemeError matches", "AuthSchemeError", IsAuthSchemeError, true},
+		{"IsAuthFailed matches", "AuthorizationFailed", IsAuthFailed, true},
+		{"IsBadRequest matches", "BadRequest", IsBadRequest, true},
+		{"IsInternal matches", "InternalError", IsInternal, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(http.StatusBadRequest, tt.code, "boom")
+			if got := tt.is(err); got != tt.want {
+				t.Fatalf("predicate(%q) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSpecificStatusCode(t *testing.T) {
+	err := New(http.StatusNotFound, "ResourceNotFound", "boom")
+
+	if !IsSpecificStatusCode(err, http.StatusNotFound) {
+		t.Fatalf("IsSpecificStatusCode(err, %d) = false, want true", http.StatusNotFound)
+	}
+	if IsSpecificStatusCode(err, http.StatusBadRequest) {
+		t.Fatalf("IsSpecificStatusCode(err, %d) = true, want false", http.StatusBadRequest)
+	}
+}
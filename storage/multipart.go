@@ -0,0 +1,158 @@
+// Package storage implements a client for the Manta object storage API.
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/joyent/triton-go/client"
+)
+
+// MultipartUploadClient implements Manta's Multipart Upload (MPU) API,
+// used to upload large objects as a series of parts rather than a
+// single buffered request body.
+type MultipartUploadClient struct {
+	Client *client.Client
+}
+
+// NewMultipartUploadClient returns a MultipartUploadClient that issues
+// requests through c.
+func NewMultipartUploadClient(c *client.Client) *MultipartUploadClient {
+	return &MultipartUploadClient{Client: c}
+}
+
+// mpuBasePath returns the account-scoped base path for the MPU API,
+// "/:login/uploads", since Manta routes are always rooted under the
+// account's login.
+func (mc *MultipartUploadClient) mpuBasePath() string {
+	return fmt.Sprintf("/%s/uploads", mc.Client.AccountName)
+}
+
+// CreateUploadInput represents the parameters for a CreateUpload
+// request.
+type CreateUploadInput struct {
+	// ObjectPath is the final Manta path the object will be committed to.
+	ObjectPath string `json:"objectPath"`
+
+	// Headers are stored alongside the upload and applied to the object
+	// once committed (for example "content-type" or Manta "m-*" metadata).
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// CreateUploadOutput is returned by CreateUpload.
+type CreateUploadOutput struct {
+	ID             string `json:"id"`
+	PartsDirectory string `json:"partsDirectory"`
+}
+
+// CreateUpload starts a new multipart upload and returns its ID along
+// with the Manta directory individual parts are uploaded under.
+func (mc *MultipartUploadClient) CreateUpload(ctx context.Context, input *CreateUploadInput) (*CreateUploadOutput, error) {
+	respReader, err := mc.Client.ExecuteRequest(ctx, http.MethodPost, mc.mpuBasePath(), input)
+	if err != nil {
+		return nil, errwrap.Wrapf("Error executing CreateUpload request: {{err}}", err)
+	}
+	defer respReader.Close()
+
+	var output CreateUploadOutput
+	if err := json.NewDecoder(respReader).Decode(&output); err != nil {
+		return nil, errwrap.Wrapf("Error decoding CreateUpload response: {{err}}", err)
+	}
+
+	return &output, nil
+}
+
+// UploadPartInput represents the parameters for an UploadPart request.
+type UploadPartInput struct {
+	// ID is the upload returned by CreateUpload.
+	ID string
+
+	// PartNum is this part's 0-indexed position in the final object.
+	PartNum int
+
+	// Body is the part's content. If it implements io.Seeker the part
+	// is retried on transient failures; otherwise it is uploaded once.
+	Body io.Reader
+
+	// ContentLength is the size of Body in bytes.
+	ContentLength int64
+
+	// ContentType is the MIME type of Body, usually
+	// "application/octet-stream".
+	ContentType string
+}
+
+// UploadPartOutput is returned by UploadPart.
+type UploadPartOutput struct {
+	// ETag must be passed, in upload order, to CommitUpload.
+	ETag string
+}
+
+// UploadPart streams a single part of a multipart upload.
+func (mc *MultipartUploadClient) UploadPart(ctx context.Context, input *UploadPartInput) (*UploadPartOutput, error) {
+	path := fmt.Sprintf("%s/%s/%d", mc.mpuBasePath(), input.ID, input.PartNum)
+
+	resp, err := mc.Client.ExecuteRequestStream(ctx, http.MethodPut, path, input.Body, input.ContentType, input.ContentLength, nil)
+	if err != nil {
+		return nil, errwrap.Wrapf("Error executing UploadPart request: {{err}}", err)
+	}
+	defer resp.Body.Close()
+
+	return &UploadPartOutput{
+		ETag: resp.Header.Get("Etag"),
+	}, nil
+}
+
+// CommitUploadInput represents the parameters for a CommitUpload
+// request.
+type CommitUploadInput struct {
+	// ID is the upload returned by CreateUpload.
+	ID string
+
+	// Parts lists the ETag returned by UploadPart for every part, in
+	// upload order.
+	Parts []string `json:"parts"`
+}
+
+// CommitUpload assembles the uploaded parts into the final object at
+// the ObjectPath given to CreateUpload.
+func (mc *MultipartUploadClient) CommitUpload(ctx context.Context, input *CommitUploadInput) error {
+	path := fmt.Sprintf("%s/%s/commit", mc.mpuBasePath(), input.ID)
+
+	respReader, err := mc.Client.ExecuteRequest(ctx, http.MethodPost, path, &commitUploadBody{Parts: input.Parts})
+	if err != nil {
+		return errwrap.Wrapf("Error executing CommitUpload request: {{err}}", err)
+	}
+	defer respReader.Close()
+
+	return nil
+}
+
+type commitUploadBody struct {
+	Parts []string `json:"parts"`
+}
+
+// AbortUploadInput represents the parameters for an AbortUpload
+// request.
+type AbortUploadInput struct {
+	// ID is the upload returned by CreateUpload.
+	ID string
+}
+
+// AbortUpload discards an in-progress multipart upload and any parts
+// already uploaded to it.
+func (mc *MultipartUploadClient) AbortUpload(ctx context.Context, input *AbortUploadInput) error {
+	path := fmt.Sprintf("%s/%s/abort", mc.mpuBasePath(), input.ID)
+
+	respReader, err := mc.Client.ExecuteRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return errwrap.Wrapf("Error executing AbortUpload request: {{err}}", err)
+	}
+	defer respReader.Close()
+
+	return nil
+}
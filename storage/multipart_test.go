@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/joyent/triton-go/authentication"
+	"github.com/joyent/triton-go/client"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type fakeSigner struct{}
+
+func (fakeSigner) Sign(dateHeader string) (string, error) {
+	return `Signature keyId="/acmecorp/keys/aa:bb:cc",algorithm="rsa-sha1",signature="fake"`, nil
+}
+
+func (fakeSigner) KeyFingerprint() string {
+	return "aa:bb:cc"
+}
+
+func newTestMultipartClient(t *testing.T, transport roundTripperFunc) *MultipartUploadClient {
+	t.Helper()
+
+	apiURL, err := url.Parse("https://us-east.manta.joyent.com")
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	c := &client.Client{
+		HTTPClient:  &http.Client{Transport: transport},
+		Authorizers: []authentication.Signer{fakeSigner{}},
+		APIURL:      *apiURL,
+		AccountName: "acmecorp",
+	}
+
+	return NewMultipartUploadClient(c)
+}
+
+func TestCreateUploadScopesPathUnderAccountLogin(t *testing.T) {
+	var gotPath string
+	mc := newTestMultipartClient(t, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		body := `{"id":"upload-1","partsDirectory":"/acmecorp/uploads/1"}`
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}))
+
+	output, err := mc.CreateUpload(context.Background(), &CreateUploadInput{ObjectPath: "/acmecorp/stor/object"})
+	if err != nil {
+		t.Fatalf("CreateUpload() error = %v", err)
+	}
+
+	if gotPath != "/acmecorp/uploads" {
+		t.Fatalf("request path = %q, want %q", gotPath, "/acmecorp/uploads")
+	}
+	if output.ID != "upload-1" {
+		t.Fatalf("output.ID = %q, want %q", output.ID, "upload-1")
+	}
+}
+
+func TestUploadPartReturnsETag(t *testing.T) {
+	var gotPath, gotMethod string
+	mc := newTestMultipartClient(t, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		gotMethod = req.Method
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Header:     http.Header{"Etag": []string{"part-etag"}},
+			Body:       http.NoBody,
+		}, nil
+	}))
+
+	output, err := mc.UploadPart(context.Background(), &UploadPartInput{
+		ID:            "upload-1",
+		PartNum:       0,
+		Body:          bytes.NewReader([]byte("part body")),
+		ContentLength: 9,
+		ContentType:   "application/octet-stream",
+	})
+	if err != nil {
+		t.Fatalf("UploadPart() error = %v", err)
+	}
+
+	if gotMethod != http.MethodPut {
+		t.Fatalf("request method = %q, want %q", gotMethod, http.MethodPut)
+	}
+	if gotPath != "/acmecorp/uploads/upload-1/0" {
+		t.Fatalf("request path = %q, want %q", gotPath, "/acmecorp/uploads/upload-1/0")
+	}
+	if output.ETag != "part-etag" {
+		t.Fatalf("output.ETag = %q, want %q", output.ETag, "part-etag")
+	}
+}
+
+func TestCommitUploadSendsPartsAndPath(t *testing.T) {
+	var gotPath string
+	var gotBody []byte
+	mc := newTestMultipartClient(t, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		gotBody = body
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}, nil
+	}))
+
+	err := mc.CommitUpload(context.Background(), &CommitUploadInput{
+		ID:    "upload-1",
+		Parts: []string{"etag-0", "etag-1"},
+	})
+	if err != nil {
+		t.Fatalf("CommitUpload() error = %v", err)
+	}
+
+	if gotPath != "/acmecorp/uploads/upload-1/commit" {
+		t.Fatalf("request path = %q, want %q", gotPath, "/acmecorp/uploads/upload-1/commit")
+	}
+	if !strings.Contains(string(gotBody), `"etag-0"`) || !strings.Contains(string(gotBody), `"etag-1"`) {
+		t.Fatalf("request body = %s, want it to contain both part ETags", gotBody)
+	}
+}
+
+func TestAbortUploadSendsExpectedPath(t *testing.T) {
+	var gotPath, gotMethod string
+	mc := newTestMultipartClient(t, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		gotMethod = req.Method
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}, nil
+	}))
+
+	if err := mc.AbortUpload(context.Background(), &AbortUploadInput{ID: "upload-1"}); err != nil {
+		t.Fatalf("AbortUpload() error = %v", err)
+	}
+
+	if gotMethod != http.MethodPost {
+		t.Fatalf("request method = %q, want %q", gotMethod, http.MethodPost)
+	}
+	if gotPath != "/acmecorp/uploads/upload-1/abort" {
+		t.Fatalf("request path = %q, want %q", gotPath, "/acmecorp/uploads/upload-1/abort")
+	}
+}